@@ -0,0 +1,203 @@
+package logger
+
+import "sync"
+
+// OverflowPolicy controls what an async Logger does when its buffered queue
+// is full.
+type OverflowPolicy int
+
+const (
+	// PolicyBlock blocks the caller until the worker makes room. This is the
+	// default, and matches synchronous logging semantics (no entry is ever
+	// lost) at the cost of the hot path potentially stalling.
+	PolicyBlock OverflowPolicy = iota
+	// PolicyDropOldest discards the oldest queued entry to make room for the
+	// incoming one.
+	PolicyDropOldest
+	// PolicyDropNewest discards the incoming entry, leaving the queue as is.
+	PolicyDropNewest
+)
+
+// defaultBufferSize is used when Options.Async is set without a BufferSize.
+const defaultBufferSize = 256
+
+// batchHandler is implemented by handlers that can process several Entry
+// values in one call. The async pipeline uses it, when available, to
+// amortize the cost of rendering and writing a burst of queued entries.
+type batchHandler interface {
+	HandleBatch(entries []Entry) error
+}
+
+// asyncPipeline is the background worker backing an async Logger. Debug,
+// Info, etc. enqueue onto queue instead of writing inline; the worker drains
+// it, batching whatever is pending on each wake-up, and hands the batch to
+// the Logger's Handler.
+type asyncPipeline struct {
+	logger *Logger
+	queue  chan Entry
+	flushC chan chan struct{}
+	policy OverflowPolicy
+
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+func newAsyncPipeline(l *Logger, bufferSize int, policy OverflowPolicy) *asyncPipeline {
+
+	if bufferSize <= 0 {
+		bufferSize = defaultBufferSize
+	}
+
+	p := &asyncPipeline{
+		logger: l,
+		queue:  make(chan Entry, bufferSize),
+		flushC: make(chan chan struct{}),
+		policy: policy,
+		done:   make(chan struct{}),
+	}
+
+	p.wg.Add(1)
+	go p.run()
+
+	return p
+}
+
+// enqueue applies the pipeline's OverflowPolicy and hands e to the worker. It
+// returns promptly without enqueuing once the pipeline has been closed,
+// instead of blocking forever on a queue nothing drains anymore.
+func (p *asyncPipeline) enqueue(e Entry) {
+
+	switch p.policy {
+	case PolicyDropNewest:
+		select {
+		case p.queue <- e:
+		case <-p.done:
+		default:
+		}
+
+	case PolicyDropOldest:
+		for {
+			select {
+			case p.queue <- e:
+				return
+			case <-p.done:
+				return
+			default:
+				select {
+				case <-p.queue:
+				default:
+				}
+			}
+		}
+
+	default: // PolicyBlock
+		select {
+		case p.queue <- e:
+		case <-p.done:
+		}
+	}
+}
+
+func (p *asyncPipeline) run() {
+
+	defer p.wg.Done()
+
+	for {
+		select {
+		case e, ok := <-p.queue:
+			if !ok {
+				return
+			}
+			p.process(p.drain(e))
+
+		case ack := <-p.flushC:
+			p.process(p.drainPending())
+			close(ack)
+
+		case <-p.done:
+			p.process(p.drainPending())
+			return
+		}
+	}
+}
+
+// drain collects first (already received from the queue) plus anything else
+// ready without blocking, so a burst of log calls is processed as one batch.
+func (p *asyncPipeline) drain(first Entry) []Entry {
+
+	batch := []Entry{first}
+
+	for {
+		select {
+		case e, ok := <-p.queue:
+			if !ok {
+				return batch
+			}
+			batch = append(batch, e)
+		default:
+			return batch
+		}
+	}
+}
+
+func (p *asyncPipeline) drainPending() []Entry {
+
+	var batch []Entry
+
+	for {
+		select {
+		case e, ok := <-p.queue:
+			if !ok {
+				return batch
+			}
+			batch = append(batch, e)
+		default:
+			return batch
+		}
+	}
+}
+
+func (p *asyncPipeline) process(batch []Entry) {
+
+	if len(batch) == 0 {
+		return
+	}
+
+	if bh, ok := p.logger.handler.(batchHandler); ok {
+		reportHandlerError(bh.HandleBatch(batch))
+		return
+	}
+
+	for _, e := range batch {
+		reportHandlerError(p.logger.handler.Handle(e))
+	}
+}
+
+// flush blocks until every entry enqueued before the call has been
+// processed. It returns promptly if the pipeline has already been closed,
+// instead of blocking forever on a worker that has already exited.
+func (p *asyncPipeline) flush() {
+
+	ack := make(chan struct{})
+
+	select {
+	case p.flushC <- ack:
+	case <-p.done:
+		return
+	}
+
+	select {
+	case <-ack:
+	case <-p.done:
+	}
+}
+
+// close stops the worker after it has processed everything still queued. It
+// is idempotent and safe to call from a signal handler.
+func (p *asyncPipeline) close() {
+	p.closeOnce.Do(func() {
+		close(p.done)
+		p.wg.Wait()
+	})
+}
@@ -0,0 +1,150 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAsyncLoggerFlush(t *testing.T) {
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "async.log")
+
+	fileSink, err := NewFileSink(path, FileSinkOptions{Level: LogLevelDebug})
+	if err != nil {
+		t.Fatalf("failed to create FileSink: %v", err)
+	}
+
+	logr := NewWithOptions(Options{Level: LogLevelDebug, Sinks: []Sink{fileSink}, Async: true, BufferSize: 4})
+	defer logr.Close()
+
+	for i := 0; i < 20; i++ {
+		logr.Info("async message")
+	}
+
+	logr.Flush()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("failed to stat log file: %v", err)
+	}
+
+	if info.Size() == 0 {
+		t.Errorf("expected Flush to have drained all 20 messages to the sink")
+	}
+
+	return
+}
+
+func TestAsyncLoggerDropNewest(t *testing.T) {
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "drop.log")
+
+	fileSink, err := NewFileSink(path, FileSinkOptions{Level: LogLevelDebug})
+	if err != nil {
+		t.Fatalf("failed to create FileSink: %v", err)
+	}
+
+	logr := NewWithOptions(Options{
+		Level:          LogLevelDebug,
+		Sinks:          []Sink{fileSink},
+		Async:          true,
+		BufferSize:     1,
+		OverflowPolicy: PolicyDropNewest,
+	})
+	defer logr.Close()
+
+	for i := 0; i < 50; i++ {
+		logr.Info("burst message")
+	}
+
+	logr.Flush()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+
+	lines := strings.Count(string(data), "burst message")
+
+	if lines == 0 {
+		t.Errorf("expected PolicyDropNewest to still deliver some entries to the sink, got none")
+	}
+
+	if lines > 50 {
+		t.Errorf("expected at most 50 entries, got %d", lines)
+	}
+
+	return
+}
+
+func TestAsyncLoggerFlushAfterCloseDoesNotHang(t *testing.T) {
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "flushafterclose.log")
+
+	fileSink, err := NewFileSink(path, FileSinkOptions{Level: LogLevelDebug})
+	if err != nil {
+		t.Fatalf("failed to create FileSink: %v", err)
+	}
+
+	logr := NewWithOptions(Options{Level: LogLevelDebug, Sinks: []Sink{fileSink}, Async: true, BufferSize: 4})
+
+	logr.Info("before close")
+	logr.Close()
+
+	done := make(chan struct{})
+	go func() {
+		logr.Flush()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Flush after Close hung instead of returning promptly")
+	}
+
+	return
+}
+
+func BenchmarkLoggerSync(b *testing.B) {
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		b.Fatalf("failed to open %s: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+
+	logr := NewWithOptions(Options{Level: LogLevelInfo, Output: devNull})
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		logr.Info("benchmark message")
+	}
+}
+
+func BenchmarkLoggerAsync(b *testing.B) {
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		b.Fatalf("failed to open %s: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+
+	logr := NewWithOptions(Options{Level: LogLevelInfo, Output: devNull, Async: true, BufferSize: 1024})
+	defer logr.Close()
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		logr.Info("benchmark message")
+	}
+
+	logr.Flush()
+}
@@ -0,0 +1,101 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestIncludeCallerText(t *testing.T) {
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "caller.log")
+
+	fileSink, err := NewFileSink(path, FileSinkOptions{Level: LogLevelDebug})
+	if err != nil {
+		t.Fatalf("failed to create FileSink: %v", err)
+	}
+	defer fileSink.Close()
+
+	logr := NewWithOptions(Options{Level: LogLevelDebug, Sinks: []Sink{fileSink}, IncludeCaller: true})
+
+	callerLine := 25
+	logr.Info("with caller") // must stay on callerLine
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+
+	want := "caller_test.go:" + strconv.Itoa(callerLine)
+	if !strings.Contains(string(data), want) {
+		t.Errorf("expected log line to contain %q, got %q", want, string(data))
+	}
+
+	return
+}
+
+func TestIncludeCallerJSON(t *testing.T) {
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "caller.jsonl")
+
+	logr := NewWithOptions(Options{
+		Level:         LogLevelDebug,
+		Output:        mustCreate(t, path),
+		Format:        FormatJSON,
+		IncludeCaller: true,
+	})
+
+	logr.Info("with caller json")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+
+	if !strings.Contains(string(data), `"source"`) {
+		t.Errorf("expected JSON entry to contain a source field, got %q", string(data))
+	}
+
+	if !strings.Contains(string(data), "caller_test.go") {
+		t.Errorf("expected JSON source.file to reference caller_test.go, got %q", string(data))
+	}
+
+	return
+}
+
+func TestCallerDisabledByDefault(t *testing.T) {
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "nocaller.log")
+
+	logr := NewWithOptions(Options{Level: LogLevelDebug, Output: mustCreate(t, path)})
+
+	logr.Info("no caller")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+
+	if strings.Contains(string(data), "caller_test.go") {
+		t.Errorf("expected no caller info without IncludeCaller, got %q", string(data))
+	}
+
+	return
+}
+
+func mustCreate(t *testing.T, path string) *os.File {
+	t.Helper()
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create %s: %v", path, err)
+	}
+	t.Cleanup(func() { f.Close() })
+
+	return f
+}
@@ -2,8 +2,11 @@
 package logger
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"sync"
 	"time"
@@ -34,19 +37,104 @@ const (
 	LogDateFormat string = "2006-01-02 15:04:05.000 MST"
 )
 
+// Format selects how a Logger renders its entries.
+type Format int
+
+const (
+	// FormatText renders entries in the traditional space-delimited format.
+	FormatText Format = iota
+	// FormatJSON renders entries as a single line of JSON, modeled on log/slog's
+	// JSON handler: a "time", "level" and "msg" field plus any accumulated attributes.
+	FormatJSON
+)
+
 var logFatal = Logger.Fatal
 
+// Attr is a key/value pair attached to a Logger via With, and included on
+// every record the Logger (or one derived from it) emits.
+type Attr struct {
+	Key   string
+	Value any
+}
+
+// CallerInfo is the resolved source location of a log call, captured when
+// the Logger has caller capture enabled.
+type CallerInfo struct {
+	File string
+	Line int
+	Func string
+}
+
+// Entry is a single log record passed to a Handler.
+type Entry struct {
+	Time   time.Time
+	Level  LogLevel
+	Msg    string
+	Attrs  []Attr
+	Caller CallerInfo
+	Logger string
+}
+
+// Handler renders and writes an Entry. Implementations must be safe for
+// concurrent use, since a Logger may be shared across goroutines. Plug in a
+// custom Handler (e.g. an adapter over an slog.Handler) via Options.Handler
+// to redirect entries away from the built-in text/JSON rendering.
+type Handler interface {
+	Handle(e Entry) error
+}
+
 // Logger is a custom log writer that adds a timestamp to each log entry
 type Logger struct {
 	Level  LogLevel
 	Output *os.File
-	m      *sync.Mutex
+	Format Format
+	Sinks  []Sink
+	Async  bool
+	// IncludeCaller, when true, resolves and attaches the caller's file,
+	// line and function name to every Entry.
+	IncludeCaller bool
+	// CallerSkip adjusts how many extra stack frames to ascend past the
+	// Logger's own level methods, for callers that wrap Debug/Info/etc in
+	// their own helper functions.
+	CallerSkip int
+	// Name is the dotted name this Logger was registered under via Named,
+	// included on every record it emits. Empty for a plain New/NewWithOptions
+	// Logger.
+	Name     string
+	handler  Handler
+	attrs    []Attr
+	group    string
+	pipeline *asyncPipeline
+	m        *sync.Mutex
 }
 
 // Options are options for the Logger
 type Options struct {
 	Level  LogLevel
 	Output *os.File
+	Format Format
+	// Sinks fan a Logger's entries out to multiple destinations (a file, a
+	// network endpoint, the console, ...). If Output is also set, it is
+	// appended as an additional Sink so existing callers keep working.
+	Sinks []Sink
+	// Handler, if set, overrides the built-in Sink fan-out entirely.
+	Handler Handler
+	// Async, if true, makes log calls enqueue onto a buffered channel
+	// consumed by a background goroutine instead of writing inline. Call
+	// Flush or Close to drain it.
+	Async bool
+	// BufferSize sets the async queue's capacity. Defaults to 256.
+	BufferSize int
+	// OverflowPolicy controls what happens when the async queue is full.
+	// Defaults to PolicyBlock.
+	OverflowPolicy OverflowPolicy
+	// IncludeCaller, when true, resolves and attaches the caller's file,
+	// line and function name to every Entry.
+	IncludeCaller bool
+	// CallerSkip adjusts how many extra stack frames to ascend past the
+	// Logger's own level methods, for callers that wrap Debug/Info/etc in
+	// their own helper functions.
+	CallerSkip int
 }
 
 func (l LogLevel) String() string {
@@ -70,13 +158,189 @@ func (l LogLevel) String() string {
 	}
 }
 
+// fanoutHandler is the default Handler: it writes every Entry to each of the
+// Logger's Sinks, so Output and explicit Sinks (file, network, console, ...)
+// all stay in sync.
+type fanoutHandler struct {
+	logger *Logger
+}
+
+func (h *fanoutHandler) Handle(e Entry) error {
+
+	var firstErr error
+
+	for _, sink := range h.logger.Sinks {
+		if err := sink.Write(e); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// batchSink is implemented by Sinks that can render and write several
+// entries in one call, amortizing syscall cost for a burst of queued
+// records. It is optional: fanoutHandler falls back to calling Write per
+// entry for Sinks that don't implement it.
+type batchSink interface {
+	WriteBatch(entries []Entry) error
+}
+
+// HandleBatch implements batchHandler.
+func (h *fanoutHandler) HandleBatch(entries []Entry) error {
+
+	var firstErr error
+
+	for _, sink := range h.logger.Sinks {
+		if bs, ok := sink.(batchSink); ok {
+			if err := bs.WriteBatch(entries); err != nil && firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		for _, e := range entries {
+			if err := sink.Write(e); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	return firstErr
+}
+
+// renderJSON renders an Entry as a single line of JSON: "time", "level" and
+// "msg" plus any accumulated attributes.
+func renderJSON(e Entry) ([]byte, error) {
+
+	fields := make(map[string]any, len(e.Attrs)+3)
+	fields["time"] = e.Time.Format(time.RFC3339Nano)
+	fields["level"] = e.Level.String()
+	fields["msg"] = e.Msg
+
+	for _, attr := range e.Attrs {
+		fields[attr.Key] = attr.Value
+	}
+
+	if e.Logger != "" {
+		fields["logger"] = e.Logger
+	}
+
+	if e.Caller.File != "" {
+		fields["source"] = map[string]any{
+			"file": e.Caller.File,
+			"line": e.Caller.Line,
+			"func": e.Caller.Func,
+		}
+	}
+
+	line, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(line, '\n'), nil
+}
+
+// renderEntry renders e as text or JSON depending on format, the single path
+// every Sink uses so an Options.Format of FormatJSON is honored consistently
+// whether an entry goes through the implicit Output or an explicit Sink.
+func renderEntry(format Format, e Entry) ([]byte, error) {
+	if format == FormatJSON {
+		return renderJSON(e)
+	}
+	return []byte(buildMessage(e.Time, e.Level, e.Logger, e.Caller, e.Attrs, e.Msg)), nil
+}
+
+// formatSink is implemented by built-in Sinks whose rendering depends on a
+// Format, so NewWithOptions can propagate the owning Logger's Format to any
+// explicit Sinks that haven't set their own.
+type formatSink interface {
+	setFormat(f Format)
+}
+
+// outputSink renders an Entry according to the Logger's Format and writes it
+// to the Logger's Output, preserving the original single-Output behavior for
+// loggers that haven't opted into explicit Sinks.
+type outputSink struct {
+	logger *Logger
+}
+
+func (s *outputSink) Write(e Entry) error {
+
+	message, err := renderEntry(s.logger.Format, e)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.logger.Write(message)
+	return err
+}
+
+func (s *outputSink) Close() error {
+	return nil
+}
+
+// WriteBatch implements batchSink: it renders every entry and issues a
+// single Write, instead of one per entry.
+func (s *outputSink) WriteBatch(entries []Entry) error {
+
+	var buf []byte
+
+	for _, e := range entries {
+		rendered, err := renderEntry(s.logger.Format, e)
+		if err != nil {
+			return err
+		}
+		buf = append(buf, rendered...)
+	}
+
+	if len(buf) == 0 {
+		return nil
+	}
+
+	_, err := s.logger.Write(buf)
+	return err
+}
+
+// attrsText renders attrs as space-separated key=value pairs, quoting values
+// that contain whitespace.
+func attrsText(attrs []Attr) string {
+
+	if len(attrs) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+
+	for _, attr := range attrs {
+		value := fmt.Sprintf("%v", attr.Value)
+		if strings.ContainsAny(value, " \t\n") {
+			value = fmt.Sprintf("%q", value)
+		}
+		sb.WriteString(" ")
+		sb.WriteString(attr.Key)
+		sb.WriteString("=")
+		sb.WriteString(value)
+	}
+
+	return sb.String()
+}
+
 // buildMessage builds a log message with a prefix and args passed to it
 // The arguments are separated by a space
-func buildMessage(l LogLevel, a ...any) string {
+func buildMessage(t time.Time, l LogLevel, name string, caller CallerInfo, attrs []Attr, a ...any) string {
 
 	var message string
 
-	prefix := fmt.Sprintf(time.Now().Format(LogDateFormat)) + " " + l.String()[0:1] + " "
+	prefix := fmt.Sprintf(t.Format(LogDateFormat)) + " " + l.String()[0:1]
+	if name != "" {
+		prefix += " [" + name + "]"
+	}
+	if caller.File != "" {
+		prefix += " " + filepath.Base(caller.File) + ":" + fmt.Sprint(caller.Line)
+	}
+	prefix += " "
 	prefixLength := len(prefix)
 
 	for _, v := range a {
@@ -92,17 +356,28 @@ func buildMessage(l LogLevel, a ...any) string {
 		}
 	}
 
+	message = strings.TrimRight(message, "\n")
+	if message == "" {
+		// No args produced any text (e.g. Info() or Info("")), but the prefix
+		// itself must still reach the sink, whether or not attrs follow.
+		message = strings.TrimRight(prefix, " ")
+	}
+
+	message += attrsText(attrs)
+	if message != "" {
+		message += "\n"
+	}
 	message = strings.TrimRight(message, " ")
-	// if message[len(message)-1] != ' ' {
-	// 	message += " "
-	// }
 
 	return message
 }
 
 // New creates a new Logger
 func New() *Logger {
-	return &Logger{Level: LogLevelInfo, Output: os.Stderr, m: &sync.Mutex{}}
+	l := &Logger{Level: LogLevelInfo, Output: os.Stderr, Format: FormatText, m: &sync.Mutex{}}
+	l.Sinks = []Sink{&outputSink{logger: l}}
+	l.handler = &fanoutHandler{logger: l}
+	return l
 }
 
 // NewWithOptions creates a new Logger with options
@@ -112,11 +387,105 @@ func NewWithOptions(opts Options) *Logger {
 		opts.Level = LogLevelInfo
 	}
 
-	if opts.Output == nil {
+	if opts.Output == nil && opts.Sinks == nil {
 		opts.Output = os.Stderr
 	}
 
-	return &Logger{Level: opts.Level, Output: opts.Output, m: &sync.Mutex{}}
+	l := &Logger{
+		Level:         opts.Level,
+		Output:        opts.Output,
+		Format:        opts.Format,
+		IncludeCaller: opts.IncludeCaller,
+		CallerSkip:    opts.CallerSkip,
+		m:             &sync.Mutex{},
+	}
+
+	l.Sinks = append(l.Sinks, opts.Sinks...)
+	if opts.Output != nil {
+		l.Sinks = append(l.Sinks, &outputSink{logger: l})
+	}
+
+	for _, sink := range opts.Sinks {
+		if fs, ok := sink.(formatSink); ok && opts.Format != FormatText {
+			fs.setFormat(opts.Format)
+		}
+	}
+
+	if opts.Handler != nil {
+		l.handler = opts.Handler
+	} else {
+		l.handler = &fanoutHandler{logger: l}
+	}
+
+	if opts.Async {
+		l.Async = true
+		l.pipeline = newAsyncPipeline(l, opts.BufferSize, opts.OverflowPolicy)
+	}
+
+	return l
+}
+
+// With returns a derived Logger that carries attrs (key/value pairs, as in
+// log/slog) on every record it emits, in addition to any attributes already
+// present on the receiver. The receiver is left unchanged.
+func (writer *Logger) With(attrs ...any) *Logger {
+
+	kvs := make([]Attr, 0, len(attrs)/2)
+	for i := 0; i+1 < len(attrs); i += 2 {
+		key, _ := attrs[i].(string)
+		if writer.group != "" {
+			key = writer.group + "." + key
+		}
+		kvs = append(kvs, Attr{Key: key, Value: attrs[i+1]})
+	}
+
+	child := *writer
+	child.attrs = append(append([]Attr{}, writer.attrs...), kvs...)
+	child.Sinks = append([]Sink{}, writer.Sinks...)
+
+	derived := &child
+	derived.rebind()
+
+	return derived
+}
+
+// WithGroup returns a derived Logger whose subsequent With attributes are
+// namespaced under name (dotted, for nested groups). The receiver is left
+// unchanged.
+func (writer *Logger) WithGroup(name string) *Logger {
+
+	child := *writer
+	child.Sinks = append([]Sink{}, writer.Sinks...)
+
+	if writer.group != "" {
+		child.group = writer.group + "." + name
+	} else {
+		child.group = name
+	}
+
+	derived := &child
+	derived.rebind()
+
+	return derived
+}
+
+// rebind repoints the built-in outputSink and fanoutHandler (if present) at
+// l itself. With/WithGroup copy the receiver's struct, but that copy still
+// shares its Sinks slice and handler with the Logger it was derived from; an
+// outputSink or fanoutHandler left pointing at the original would keep
+// rendering through the original's Output/Format/Sinks even after l.SetOutput
+// or l.SetLevel, so a derived Logger never actually became independent.
+func (l *Logger) rebind() {
+
+	for i, sink := range l.Sinks {
+		if _, ok := sink.(*outputSink); ok {
+			l.Sinks[i] = &outputSink{logger: l}
+		}
+	}
+
+	if _, ok := l.handler.(*fanoutHandler); ok {
+		l.handler = &fanoutHandler{logger: l}
+	}
 }
 
 // SetLevel sets the log level
@@ -130,6 +499,16 @@ func (writer *Logger) SetLevel(level LogLevel) {
 	return
 }
 
+// SetCaller enables or disables caller file/line/function capture.
+func (writer *Logger) SetCaller(enabled bool) {
+
+	writer.m.Lock()
+	writer.IncludeCaller = enabled
+	writer.m.Unlock()
+
+	return
+}
+
 // SetOutput sets the output file for the logger
 func (writer *Logger) SetOutput(file *os.File) {
 
@@ -164,15 +543,114 @@ func (writer Logger) Write(bytes []byte) (int, error) {
 	return writer.Output.Write(bytes)
 }
 
+// callerBaseSkip accounts for the frames between runtime.Caller and the
+// user's call site: captureCaller itself, emit, and the exported level
+// method (Debug, Debugf, ...) that called emit.
+const callerBaseSkip = 3
+
+// captureCaller resolves the file, line and function name of the log call
+// that is skip frames above emit's caller.
+func captureCaller(skip int) CallerInfo {
+
+	pc, file, line, ok := runtime.Caller(callerBaseSkip + skip)
+	if !ok {
+		return CallerInfo{}
+	}
+
+	caller := CallerInfo{File: file, Line: line}
+
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		caller.Func = fn.Name()
+	}
+
+	return caller
+}
+
+// emit builds the Entry for msg and hands it to the Logger's Handler. It is
+// the common path every level method funnels through, so text and JSON
+// output (and any custom Handler) stay consistent.
+func (writer Logger) emit(level LogLevel, msg string) {
+
+	entry := Entry{Time: time.Now(), Level: level, Msg: msg, Attrs: writer.attrs, Logger: writer.Name}
+
+	if writer.IncludeCaller {
+		entry.Caller = captureCaller(writer.CallerSkip)
+	}
+
+	if writer.pipeline != nil {
+		writer.pipeline.enqueue(entry)
+		return
+	}
+
+	h := writer.handler
+	if h == nil {
+		h = &fanoutHandler{logger: &writer}
+	}
+
+	reportHandlerError(h.Handle(entry))
+}
+
+// reportHandlerError is how a Handler/Sink error surfaces: a single offline
+// or misbehaving Sink (a ConnSink whose peer is down, say) must not bring
+// down a process that is also logging to other, healthy Sinks, so this
+// writes straight to stderr rather than panicking.
+func reportHandlerError(err error) {
+	if err == nil {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "logger: %v\n", err)
+}
+
+// Flush blocks until every entry enqueued so far by this Logger's async
+// pipeline has been processed. It is a no-op for a synchronous Logger.
+func (writer *Logger) Flush() {
+	if writer.pipeline != nil {
+		writer.pipeline.flush()
+	}
+}
+
+// Close flushes and stops the async pipeline, if any, then closes every
+// Sink. It is safe to call from a signal handler.
+func (writer *Logger) Close() error {
+
+	if writer.pipeline != nil {
+		writer.pipeline.close()
+	}
+
+	var firstErr error
+
+	for _, sink := range writer.Sinks {
+		if err := sink.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// joinArgs concatenates a Debug/Info/...-style arg list into a single message
+// string. String args are appended as they are, other args are formatted with
+// fmt.Sprint, and no separator is put between args.
+func joinArgs(a ...any) string {
+
+	var sb strings.Builder
+
+	for _, v := range a {
+		if s, ok := v.(string); ok {
+			sb.WriteString(s)
+		} else {
+			sb.WriteString(fmt.Sprint(v))
+		}
+	}
+
+	return sb.String()
+}
+
 // Debug logs a debug message
 func (writer Logger) Debug(a ...any) {
 
 	if writer.Level >= LogLevelDebug {
-		message := buildMessage(LogLevelDebug, a...)
-		_, err := writer.Write([]byte(message))
-		if err != nil {
-			panic(err)
-		}
+		writer.emit(LogLevelDebug, joinArgs(a...))
 	}
 
 	return
@@ -182,12 +660,7 @@ func (writer Logger) Debug(a ...any) {
 func (writer Logger) Debugf(format string, a ...any) {
 
 	if writer.Level >= LogLevelDebug {
-		msg := fmt.Sprintf(format, a...)
-		message := buildMessage(LogLevelDebug, msg)
-		_, err := writer.Write([]byte(message))
-		if err != nil {
-			panic(err)
-		}
+		writer.emit(LogLevelDebug, fmt.Sprintf(format, a...))
 	}
 
 	return
@@ -197,11 +670,7 @@ func (writer Logger) Debugf(format string, a ...any) {
 func (writer Logger) Error(a ...any) {
 
 	if writer.Level >= LogLevelError {
-		message := buildMessage(LogLevelError, a...)
-		_, err := writer.Write([]byte(message))
-		if err != nil {
-			panic(err)
-		}
+		writer.emit(LogLevelError, joinArgs(a...))
 	}
 
 	return
@@ -211,12 +680,7 @@ func (writer Logger) Error(a ...any) {
 func (writer Logger) Errorf(format string, a ...any) {
 
 	if writer.Level >= LogLevelError {
-		msg := fmt.Sprintf(format, a...)
-		message := buildMessage(LogLevelError, msg)
-		_, err := writer.Write([]byte(message))
-		if err != nil {
-			panic(err)
-		}
+		writer.emit(LogLevelError, fmt.Sprintf(format, a...))
 	}
 
 	return
@@ -225,12 +689,8 @@ func (writer Logger) Errorf(format string, a ...any) {
 // Fatal logs a fatal message
 func (writer Logger) Fatal(a ...any) {
 
-	message := buildMessage(LogLevelFatal, a...)
-	_, err := writer.Write([]byte(message))
-
-	if err != nil {
-		panic(err)
-	}
+	writer.emit(LogLevelFatal, joinArgs(a...))
+	writer.Flush()
 
 	os.Exit(1)
 }
@@ -238,13 +698,8 @@ func (writer Logger) Fatal(a ...any) {
 // Fatalf logs a fatal message with a format string
 func (writer Logger) Fatalf(format string, a ...any) {
 
-	msg := fmt.Sprintf(format, a...)
-	message := buildMessage(LogLevelFatal, msg)
-	_, err := writer.Write([]byte(message))
-
-	if err != nil {
-		panic(err)
-	}
+	writer.emit(LogLevelFatal, fmt.Sprintf(format, a...))
+	writer.Flush()
 
 	os.Exit(1)
 }
@@ -253,11 +708,7 @@ func (writer Logger) Fatalf(format string, a ...any) {
 func (writer Logger) Info(a ...any) {
 
 	if writer.Level >= LogLevelInfo {
-		message := buildMessage(LogLevelInfo, a...)
-		_, err := writer.Write([]byte(message))
-		if err != nil {
-			panic(err)
-		}
+		writer.emit(LogLevelInfo, joinArgs(a...))
 	}
 
 	return
@@ -267,12 +718,7 @@ func (writer Logger) Info(a ...any) {
 func (writer Logger) Infof(format string, a ...any) {
 
 	if writer.Level >= LogLevelInfo {
-		msg := fmt.Sprintf(format, a...)
-		message := buildMessage(LogLevelInfo, msg)
-		_, err := writer.Write([]byte(message))
-		if err != nil {
-			panic(err)
-		}
+		writer.emit(LogLevelInfo, fmt.Sprintf(format, a...))
 	}
 
 	return
@@ -282,11 +728,7 @@ func (writer Logger) Infof(format string, a ...any) {
 func (writer Logger) Trace(a ...any) {
 
 	if writer.Level >= LogLevelTrace {
-		message := buildMessage(LogLevelTrace, a...)
-		_, err := writer.Write([]byte(message))
-		if err != nil {
-			panic(err)
-		}
+		writer.emit(LogLevelTrace, joinArgs(a...))
 	}
 
 	return
@@ -296,12 +738,7 @@ func (writer Logger) Trace(a ...any) {
 func (writer Logger) Tracef(format string, a ...any) {
 
 	if writer.Level >= LogLevelTrace {
-		msg := fmt.Sprintf(format, a...)
-		message := buildMessage(LogLevelTrace, msg)
-		_, err := writer.Write([]byte(message))
-		if err != nil {
-			panic(err)
-		}
+		writer.emit(LogLevelTrace, fmt.Sprintf(format, a...))
 	}
 
 	return
@@ -311,11 +748,7 @@ func (writer Logger) Tracef(format string, a ...any) {
 func (writer Logger) Verbose(a ...any) {
 
 	if writer.Level >= LogLevelVerbose {
-		message := buildMessage(LogLevelVerbose, a...)
-		_, err := writer.Write([]byte(message))
-		if err != nil {
-			panic(err)
-		}
+		writer.emit(LogLevelVerbose, joinArgs(a...))
 	}
 
 	return
@@ -325,12 +758,7 @@ func (writer Logger) Verbose(a ...any) {
 func (writer Logger) Verbosef(format string, a ...any) {
 
 	if writer.Level >= LogLevelVerbose {
-		msg := fmt.Sprintf(format, a...)
-		message := buildMessage(LogLevelVerbose, msg)
-		_, err := writer.Write([]byte(message))
-		if err != nil {
-			panic(err)
-		}
+		writer.emit(LogLevelVerbose, fmt.Sprintf(format, a...))
 	}
 
 	return
@@ -340,11 +768,7 @@ func (writer Logger) Verbosef(format string, a ...any) {
 func (writer Logger) Warn(a ...any) {
 
 	if writer.Level >= LogLevelWarn {
-		message := buildMessage(LogLevelWarn, a...)
-		_, err := writer.Write([]byte(message))
-		if err != nil {
-			panic(err)
-		}
+		writer.emit(LogLevelWarn, joinArgs(a...))
 	}
 
 	return
@@ -354,12 +778,7 @@ func (writer Logger) Warn(a ...any) {
 func (writer Logger) Warnf(format string, a ...any) {
 
 	if writer.Level >= LogLevelWarn {
-		msg := fmt.Sprintf(format, a...)
-		message := buildMessage(LogLevelWarn, msg)
-		_, err := writer.Write([]byte(message))
-		if err != nil {
-			panic(err)
-		}
+		writer.emit(LogLevelWarn, fmt.Sprintf(format, a...))
 	}
 
 	return
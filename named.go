@@ -0,0 +1,100 @@
+package logger
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// namedRegistry tracks the hierarchy of Loggers created via Named, plus any
+// level overrides installed via SetLevelFor.
+type namedRegistry struct {
+	m         sync.Mutex
+	loggers   map[string]*Logger
+	overrides map[string]LogLevel // glob pattern -> level; setting a pattern again replaces its level
+}
+
+var registry = &namedRegistry{
+	loggers:   make(map[string]*Logger),
+	overrides: make(map[string]LogLevel),
+}
+
+// Named returns the Logger registered under the dotted name (e.g. "db.pool"),
+// creating it and any missing ancestors first. A newly created Logger
+// inherits its parent's Level (the Logger for "db" in the "db.pool" example)
+// unless a pattern registered via SetLevelFor matches its name first.
+func Named(name string) *Logger {
+	registry.m.Lock()
+	defer registry.m.Unlock()
+	return registry.named(name)
+}
+
+// Get returns the Logger previously registered under name via Named, or nil
+// if there isn't one.
+func Get(name string) *Logger {
+	registry.m.Lock()
+	defer registry.m.Unlock()
+	return registry.loggers[name]
+}
+
+// SetLevelFor sets level on every registered Logger whose name matches
+// pattern (glob syntax, e.g. "db.*"), and remembers pattern so it also
+// applies to matching Loggers created by Named afterwards.
+func SetLevelFor(pattern string, level LogLevel) {
+
+	registry.m.Lock()
+	defer registry.m.Unlock()
+
+	registry.overrides[pattern] = level
+
+	for name, l := range registry.loggers {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			l.SetLevel(level)
+		}
+	}
+}
+
+func (r *namedRegistry) named(name string) *Logger {
+
+	if l, ok := r.loggers[name]; ok {
+		return l
+	}
+
+	var level LogLevel = LogLevelInfo
+	if parent := parentName(name); parent != "" {
+		level = r.named(parent).Level
+	}
+
+	if override, ok := r.levelFor(name); ok {
+		level = override
+	}
+
+	l := New()
+	l.Level = level
+	l.Name = name
+
+	r.loggers[name] = l
+
+	return l
+}
+
+// levelFor returns the level of an override pattern matching name. When
+// several patterns match, which of them is used is not defined (map
+// iteration order).
+func (r *namedRegistry) levelFor(name string) (LogLevel, bool) {
+
+	for pattern, level := range r.overrides {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return level, true
+		}
+	}
+
+	return 0, false
+}
+
+func parentName(name string) string {
+	if ix := strings.LastIndex(name, "."); ix >= 0 {
+		return name[:ix]
+	}
+	return ""
+}
@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNamedInheritsParentLevel(t *testing.T) {
+
+	db := Named("inherits.db")
+	db.SetLevel(LogLevelDebug)
+
+	pool := Named("inherits.db.pool")
+
+	if pool.Level != LogLevelDebug {
+		t.Errorf("expected inherits.db.pool to inherit inherits.db's level (%v), got %v", LogLevelDebug, pool.Level)
+	}
+
+	return
+}
+
+func TestGetReturnsRegisteredLogger(t *testing.T) {
+
+	created := Named("gettest.svc")
+
+	if got := Get("gettest.svc"); got != created {
+		t.Errorf("expected Get to return the same *Logger as Named, got a different instance")
+	}
+
+	if got := Get("gettest.nonexistent"); got != nil {
+		t.Errorf("expected Get to return nil for an unregistered name, got %v", got)
+	}
+
+	return
+}
+
+func TestSetLevelForGlobMatchesExisting(t *testing.T) {
+
+	Named("glob.api")
+	Named("glob.api.handlers")
+
+	SetLevelFor("glob.api*", LogLevelTrace)
+
+	if Get("glob.api").Level != LogLevelTrace {
+		t.Errorf("expected glob.api to pick up the SetLevelFor override")
+	}
+
+	if Get("glob.api.handlers").Level != LogLevelTrace {
+		t.Errorf("expected glob.api.handlers to pick up the SetLevelFor override")
+	}
+
+	return
+}
+
+func TestSetLevelForAppliesToFutureLoggers(t *testing.T) {
+
+	SetLevelFor("future.svc*", LogLevelError)
+
+	svc := Named("future.svc")
+
+	if svc.Level != LogLevelError {
+		t.Errorf("expected a Logger created after SetLevelFor to pick up a matching override, got %v", svc.Level)
+	}
+
+	return
+}
+
+func TestNamedLoggerIncludesNameInOutput(t *testing.T) {
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "named.log")
+
+	named := Named("output.worker")
+	named.SetLevel(LogLevelDebug)
+	named.SetOutput(mustCreate(t, path))
+
+	named.Info("hello from worker")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+
+	if !strings.Contains(string(data), "[output.worker]") {
+		t.Errorf("expected log line to contain the logger name, got %q", string(data))
+	}
+
+	return
+}
+
+func TestNamedLoggerJSONIncludesLoggerField(t *testing.T) {
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "named.jsonl")
+
+	named := Named("output.jsonworker")
+	named.SetLevel(LogLevelDebug)
+	named.Format = FormatJSON
+	named.SetOutput(mustCreate(t, path))
+
+	named.Info("hello json")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+
+	if !strings.Contains(string(data), `"logger":"output.jsonworker"`) {
+		t.Errorf("expected JSON entry to contain the logger field, got %q", string(data))
+	}
+
+	return
+}
@@ -0,0 +1,459 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sink is a destination a Logger can fan an Entry out to. A Logger may hold
+// several Sinks at once (e.g. the console, a rotating file, and a network
+// endpoint) and each Sink filters independently via its own minimum Level,
+// the same convention Logger.Level uses: an Entry reaches the Sink only when
+// the Sink's Level is at least as verbose as the Entry's.
+type Sink interface {
+	Write(e Entry) error
+	Close() error
+}
+
+// ansiReset clears any color set by ConsoleSink.
+const ansiReset = "\x1b[0m"
+
+func colorForLevel(level LogLevel) string {
+	switch level {
+	case LogLevelFatal:
+		return "\x1b[1;31m" // bold red
+	case LogLevelError:
+		return "\x1b[31m" // red
+	case LogLevelWarn:
+		return "\x1b[33m" // yellow
+	case LogLevelInfo:
+		return "\x1b[32m" // green
+	case LogLevelVerbose:
+		return "\x1b[36m" // cyan
+	case LogLevelDebug:
+		return "\x1b[34m" // blue
+	case LogLevelTrace:
+		return "\x1b[35m" // magenta
+	default:
+		return ""
+	}
+}
+
+// ConsoleSink writes entries to an *os.File as text (optionally colored by
+// level) or JSON, per Format.
+type ConsoleSink struct {
+	Level  LogLevel
+	Color  bool
+	Output *os.File
+	Format Format
+
+	m sync.Mutex
+}
+
+func (s *ConsoleSink) setFormat(f Format) { s.Format = f }
+
+// NewConsoleSink creates a ConsoleSink writing to output (os.Stderr if nil).
+func NewConsoleSink(output *os.File, level LogLevel, color bool) *ConsoleSink {
+
+	if output == nil {
+		output = os.Stderr
+	}
+
+	return &ConsoleSink{Level: level, Color: color, Output: output}
+}
+
+// Write implements Sink.
+func (s *ConsoleSink) Write(e Entry) error {
+
+	if s.Level < e.Level {
+		return nil
+	}
+
+	rendered, err := renderEntry(s.Format, e)
+	if err != nil {
+		return err
+	}
+
+	message := string(rendered)
+	if s.Format != FormatJSON && s.Color {
+		message = colorForLevel(e.Level) + strings.TrimSuffix(message, "\n") + ansiReset + "\n"
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	_, err = s.Output.WriteString(message)
+	return err
+}
+
+// Close implements Sink. The console's underlying file is not ours to close.
+func (s *ConsoleSink) Close() error {
+	return nil
+}
+
+// WriteBatch implements batchSink.
+func (s *ConsoleSink) WriteBatch(entries []Entry) error {
+
+	var sb strings.Builder
+
+	for _, e := range entries {
+		if s.Level < e.Level {
+			continue
+		}
+
+		rendered, err := renderEntry(s.Format, e)
+		if err != nil {
+			return err
+		}
+
+		message := string(rendered)
+		if s.Format != FormatJSON && s.Color {
+			message = colorForLevel(e.Level) + strings.TrimSuffix(message, "\n") + ansiReset + "\n"
+		}
+
+		sb.WriteString(message)
+	}
+
+	if sb.Len() == 0 {
+		return nil
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	_, err := s.Output.WriteString(sb.String())
+	return err
+}
+
+// FileSinkOptions configures rotation for a FileSink.
+type FileSinkOptions struct {
+	Level LogLevel
+	// Format selects text or JSON rendering, same as Options.Format.
+	Format Format
+	// MaxBytes rotates the active file once appending would exceed this size.
+	// Zero disables size-based rotation.
+	MaxBytes int64
+	// MaxFiles caps how many rotated files are retained; the oldest are
+	// removed first. Zero keeps them all.
+	MaxFiles int
+	// Daily rotates the active file at local midnight, in addition to
+	// MaxBytes.
+	Daily bool
+	// Gzip compresses rotated files.
+	Gzip bool
+}
+
+// FileSink writes entries as text or JSON, per Format, to a file, rotating it by size and/or
+// daily, optionally gzipping rotated files and pruning old ones.
+type FileSink struct {
+	Level  LogLevel
+	Format Format
+
+	path string
+	opts FileSinkOptions
+
+	m        sync.Mutex
+	file     *os.File
+	size     int64
+	openedOn time.Time
+}
+
+func (s *FileSink) setFormat(f Format) { s.Format = f }
+
+// NewFileSink opens (or creates) path for appending and returns a FileSink
+// that rotates it per opts.
+func NewFileSink(path string, opts FileSinkOptions) (*FileSink, error) {
+
+	s := &FileSink{Level: opts.Level, Format: opts.Format, path: path, opts: opts}
+
+	if err := s.openCurrent(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *FileSink) openCurrent() error {
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	s.file = file
+	s.size = info.Size()
+	s.openedOn = time.Now()
+
+	return nil
+}
+
+// Write implements Sink.
+func (s *FileSink) Write(e Entry) error {
+
+	if s.Level < e.Level {
+		return nil
+	}
+
+	message, err := renderEntry(s.Format, e)
+	if err != nil {
+		return err
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.shouldRotate(len(message)) {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.file.Write(message)
+	s.size += int64(n)
+
+	return err
+}
+
+func (s *FileSink) shouldRotate(nextLen int) bool {
+
+	if s.opts.MaxBytes > 0 && s.size+int64(nextLen) > s.opts.MaxBytes {
+		return true
+	}
+
+	if s.opts.Daily && time.Now().YearDay() != s.openedOn.YearDay() {
+		return true
+	}
+
+	return false
+}
+
+func (s *FileSink) rotate() error {
+
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+
+	rotated := fmt.Sprintf("%s.%s", s.path, time.Now().Format("20060102-150405"))
+	if err := os.Rename(s.path, rotated); err != nil {
+		return err
+	}
+
+	if s.opts.Gzip {
+		if err := gzipFile(rotated); err != nil {
+			return err
+		}
+	}
+
+	if s.opts.MaxFiles > 0 {
+		s.pruneOldFiles()
+	}
+
+	return s.openCurrent()
+}
+
+func (s *FileSink) pruneOldFiles() {
+
+	matches, err := filepath.Glob(s.path + ".*")
+	if err != nil {
+		return
+	}
+
+	sort.Strings(matches)
+
+	if len(matches) <= s.opts.MaxFiles {
+		return
+	}
+
+	for _, stale := range matches[:len(matches)-s.opts.MaxFiles] {
+		os.Remove(stale)
+	}
+}
+
+func gzipFile(path string) error {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	out, err := os.Create(path + ".gz")
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	gw := gzip.NewWriter(out)
+	if _, err := gw.Write(data); err != nil {
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+// Close implements Sink.
+func (s *FileSink) Close() error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.file.Close()
+}
+
+// WriteBatch implements batchSink.
+func (s *FileSink) WriteBatch(entries []Entry) error {
+
+	var buf []byte
+
+	for _, e := range entries {
+		if s.Level < e.Level {
+			continue
+		}
+		rendered, err := renderEntry(s.Format, e)
+		if err != nil {
+			return err
+		}
+		buf = append(buf, rendered...)
+	}
+
+	if len(buf) == 0 {
+		return nil
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.shouldRotate(len(buf)) {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.file.Write(buf)
+	s.size += int64(n)
+
+	return err
+}
+
+// ConnSink writes entries as newline-delimited records (text or JSON, per
+// Format) to a TCP or UDP
+// endpoint, reconnecting lazily the next time Write is called after a
+// failure.
+type ConnSink struct {
+	Level   LogLevel
+	Network string // "tcp" or "udp"
+	Address string
+	Format  Format
+
+	m    sync.Mutex
+	conn net.Conn
+}
+
+func (s *ConnSink) setFormat(f Format) { s.Format = f }
+
+// NewConnSink creates a ConnSink; the connection is established lazily on
+// the first Write.
+func NewConnSink(network, address string, level LogLevel) *ConnSink {
+	return &ConnSink{Level: level, Network: network, Address: address}
+}
+
+// Write implements Sink.
+func (s *ConnSink) Write(e Entry) error {
+
+	if s.Level < e.Level {
+		return nil
+	}
+
+	message, err := renderEntry(s.Format, e)
+	if err != nil {
+		return err
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.conn == nil {
+		conn, err := net.Dial(s.Network, s.Address)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+
+	if _, err := s.conn.Write(message); err != nil {
+		s.conn.Close()
+		s.conn = nil
+		return err
+	}
+
+	return nil
+}
+
+// Close implements Sink.
+func (s *ConnSink) Close() error {
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+
+	err := s.conn.Close()
+	s.conn = nil
+
+	return err
+}
+
+// WriteBatch implements batchSink.
+func (s *ConnSink) WriteBatch(entries []Entry) error {
+
+	var buf []byte
+
+	for _, e := range entries {
+		if s.Level < e.Level {
+			continue
+		}
+		rendered, err := renderEntry(s.Format, e)
+		if err != nil {
+			return err
+		}
+		buf = append(buf, rendered...)
+	}
+
+	if len(buf) == 0 {
+		return nil
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.conn == nil {
+		conn, err := net.Dial(s.Network, s.Address)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+
+	if _, err := s.conn.Write(buf); err != nil {
+		s.conn.Close()
+		s.conn = nil
+		return err
+	}
+
+	return nil
+}
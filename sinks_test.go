@@ -0,0 +1,247 @@
+package logger
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConsoleSink(t *testing.T) {
+
+	sink := NewConsoleSink(os.Stderr, LogLevelDebug, true)
+
+	if err := sink.Write(Entry{Level: LogLevelInfo, Msg: "console sink message"}); err != nil {
+		t.Fatalf("unexpected error writing to ConsoleSink: %v", err)
+	}
+
+	if err := sink.Close(); err != nil {
+		t.Fatalf("unexpected error closing ConsoleSink: %v", err)
+	}
+
+	return
+}
+
+func TestFileSinkWritesAndRotates(t *testing.T) {
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.log")
+
+	sink, err := NewFileSink(path, FileSinkOptions{Level: LogLevelDebug, MaxBytes: 64, MaxFiles: 2})
+	if err != nil {
+		t.Fatalf("failed to create FileSink: %v", err)
+	}
+	defer sink.Close()
+
+	for i := 0; i < 10; i++ {
+		if err := sink.Write(Entry{Level: LogLevelInfo, Msg: "a message long enough to force rotation"}); err != nil {
+			t.Fatalf("unexpected error writing to FileSink: %v", err)
+		}
+	}
+
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		t.Fatalf("failed to glob rotated files: %v", err)
+	}
+
+	if len(matches) == 0 {
+		t.Errorf("expected at least one rotated file, got none")
+	}
+
+	if len(matches) > 2 {
+		t.Errorf("expected at most MaxFiles (2) rotated files, got %d", len(matches))
+	}
+
+	return
+}
+
+func TestFileSinkLevelFilter(t *testing.T) {
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "filtered.log")
+
+	sink, err := NewFileSink(path, FileSinkOptions{Level: LogLevelError})
+	if err != nil {
+		t.Fatalf("failed to create FileSink: %v", err)
+	}
+	defer sink.Close()
+
+	if err := sink.Write(Entry{Level: LogLevelDebug, Msg: "should be filtered out"}); err != nil {
+		t.Fatalf("unexpected error writing to FileSink: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("failed to stat log file: %v", err)
+	}
+
+	if info.Size() != 0 {
+		t.Errorf("expected no bytes written for a level below the sink's threshold, got %d", info.Size())
+	}
+
+	return
+}
+
+func TestNewWithOptionsSinks(t *testing.T) {
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sink.log")
+
+	fileSink, err := NewFileSink(path, FileSinkOptions{Level: LogLevelDebug})
+	if err != nil {
+		t.Fatalf("failed to create FileSink: %v", err)
+	}
+	defer fileSink.Close()
+
+	logr := NewWithOptions(Options{Level: LogLevelDebug, Sinks: []Sink{fileSink}})
+
+	logr.Info("fans out to the configured sink")
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("failed to stat log file: %v", err)
+	}
+
+	if info.Size() == 0 {
+		t.Errorf("expected the sink to receive the log entry")
+	}
+
+	return
+}
+
+func TestNewWithOptionsJSONFormatReachesExplicitSink(t *testing.T) {
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sink.jsonl")
+
+	fileSink, err := NewFileSink(path, FileSinkOptions{Level: LogLevelDebug})
+	if err != nil {
+		t.Fatalf("failed to create FileSink: %v", err)
+	}
+	defer fileSink.Close()
+
+	logr := NewWithOptions(Options{Level: LogLevelDebug, Format: FormatJSON, Sinks: []Sink{fileSink}})
+
+	logr.Info("fans out as json")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+
+	if !strings.Contains(string(data), `"msg":"fans out as json"`) {
+		t.Errorf("expected the explicit Sink to render JSON per Options.Format, got %q", string(data))
+	}
+
+	return
+}
+
+// connSinkListener accepts connections on a local TCP listener and collects
+// every newline-delimited line written to it.
+func connSinkListener(t *testing.T) (addr string, lines chan string) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start listener: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	lines = make(chan string, 16)
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			go func(conn net.Conn) {
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					lines <- scanner.Text()
+				}
+			}(conn)
+		}
+	}()
+
+	return ln.Addr().String(), lines
+}
+
+func TestConnSinkWrite(t *testing.T) {
+
+	addr, lines := connSinkListener(t)
+
+	sink := NewConnSink("tcp", addr, LogLevelDebug)
+	defer sink.Close()
+
+	if err := sink.Write(Entry{Level: LogLevelInfo, Msg: "conn sink message"}); err != nil {
+		t.Fatalf("unexpected error writing to ConnSink: %v", err)
+	}
+
+	select {
+	case line := <-lines:
+		if !strings.Contains(line, "conn sink message") {
+			t.Errorf("expected the listener to receive %q, got %q", "conn sink message", line)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the listener to receive a line")
+	}
+
+	return
+}
+
+func TestConnSinkReconnectsAfterClose(t *testing.T) {
+
+	addr, lines := connSinkListener(t)
+
+	sink := NewConnSink("tcp", addr, LogLevelDebug)
+	defer sink.Close()
+
+	if err := sink.Write(Entry{Level: LogLevelInfo, Msg: "before reconnect"}); err != nil {
+		t.Fatalf("unexpected error on first write: %v", err)
+	}
+
+	select {
+	case <-lines:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the first line")
+	}
+
+	// Simulate a dropped connection: close the underlying conn out from under
+	// the sink, the same state a failed Write leaves it in.
+	sink.m.Lock()
+	sink.conn.Close()
+	sink.conn = nil
+	sink.m.Unlock()
+
+	if err := sink.Write(Entry{Level: LogLevelInfo, Msg: "after reconnect"}); err != nil {
+		t.Fatalf("unexpected error reconnecting: %v", err)
+	}
+
+	select {
+	case line := <-lines:
+		if !strings.Contains(line, "after reconnect") {
+			t.Errorf("expected the listener to receive %q, got %q", "after reconnect", line)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the reconnected write")
+	}
+
+	return
+}
+
+func TestConnSinkDialErrorDoesNotPanic(t *testing.T) {
+
+	sink := NewConnSink("tcp", "127.0.0.1:1", LogLevelDebug)
+	defer sink.Close()
+
+	if err := sink.Write(Entry{Level: LogLevelInfo, Msg: "unreachable"}); err == nil {
+		t.Errorf("expected an error dialing an unreachable address, got nil")
+	}
+
+	return
+}
@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWithIncludesAttrsInOutput(t *testing.T) {
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "with.log")
+
+	logr := NewWithOptions(Options{Level: LogLevelDebug, Output: mustCreate(t, path)})
+
+	logr.With("req", "abc123").Info("handled request")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+
+	if !strings.Contains(string(data), "req=abc123") {
+		t.Errorf("expected log line to contain the attr, got %q", string(data))
+	}
+
+	return
+}
+
+func TestWithGroupNamespacesAttrs(t *testing.T) {
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "withgroup.log")
+
+	logr := NewWithOptions(Options{Level: LogLevelDebug, Output: mustCreate(t, path)})
+
+	logr.WithGroup("http").With("status", 200).Info("request complete")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+
+	if !strings.Contains(string(data), "http.status=200") {
+		t.Errorf("expected log line to contain the namespaced attr, got %q", string(data))
+	}
+
+	return
+}
+
+func TestDerivedLoggerSetOutputIsIndependent(t *testing.T) {
+
+	dir := t.TempDir()
+	basePath := filepath.Join(dir, "base.log")
+	childPath := filepath.Join(dir, "child.log")
+
+	logr := NewWithOptions(Options{Level: LogLevelDebug, Output: mustCreate(t, basePath)})
+
+	child := logr.With("k", "v")
+	child.SetOutput(mustCreate(t, childPath))
+
+	child.Info("goes to the child's own output")
+
+	childData, err := os.ReadFile(childPath)
+	if err != nil {
+		t.Fatalf("failed to read child log file: %v", err)
+	}
+
+	if !strings.Contains(string(childData), "goes to the child's own output") {
+		t.Errorf("expected the entry to land in the child's own output, got %q", string(childData))
+	}
+
+	baseData, err := os.ReadFile(basePath)
+	if err != nil {
+		t.Fatalf("failed to read base log file: %v", err)
+	}
+
+	if strings.Contains(string(baseData), "goes to the child's own output") {
+		t.Errorf("expected the base Logger's output to be untouched, got %q", string(baseData))
+	}
+
+	return
+}
+
+func TestDerivedLoggerDoesNotMutateParent(t *testing.T) {
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "parent.log")
+
+	logr := NewWithOptions(Options{Level: LogLevelDebug, Output: mustCreate(t, path)})
+
+	_ = logr.With("k", "v")
+	logr.Info("from the parent, no attrs expected")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+
+	if strings.Contains(string(data), "k=v") {
+		t.Errorf("expected the parent Logger to stay unaffected by With, got %q", string(data))
+	}
+
+	return
+}